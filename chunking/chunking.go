@@ -0,0 +1,93 @@
+// Package chunking splits a Prometheus metrics exposition into
+// semantically meaningful pieces - one per Vault subsystem - instead of
+// cutting it in half by byte length. This keeps related metrics (e.g.
+// all of vault.token.*) together in a single LLM call.
+package chunking
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// subsystemPrefixes maps a Vault metric name prefix to the subsystem it
+// belongs to. Order doesn't matter; prefixes are checked independently.
+var subsystemPrefixes = map[string]string{
+	"vault_core_":    "core",
+	"vault_runtime_": "runtime",
+	"vault_expire_":  "expire",
+	"vault_token_":   "token",
+	"vault_barrier_": "storage",
+	"vault_storage_": "storage",
+	"vault_audit_":   "audit",
+	"vault_ha_":      "ha",
+}
+
+const subsystemOther = "other"
+
+// classify returns the Vault subsystem a metric belongs to, based on its
+// name prefix, or subsystemOther if none match.
+func classify(name string) string {
+	for prefix, sub := range subsystemPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return sub
+		}
+	}
+	return subsystemOther
+}
+
+// Chunk is one subsystem's worth of metrics, re-rendered as Prometheus
+// exposition text so it can be sent to the LLM like the original
+// byte-sliced chunks were.
+type Chunk struct {
+	Subsystem string
+	Text      string
+}
+
+// Group parses a Prometheus text-exposition payload and groups the
+// resulting metric families by Vault subsystem, returning one Chunk per
+// subsystem in a stable, sorted order.
+func Group(data []byte) ([]Chunk, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing prometheus metrics: %v", err)
+	}
+
+	grouped := make(map[string][]*dto.MetricFamily)
+	for name, fam := range families {
+		sub := classify(name)
+		grouped[sub] = append(grouped[sub], fam)
+	}
+
+	subs := make([]string, 0, len(grouped))
+	for sub := range grouped {
+		subs = append(subs, sub)
+	}
+	sort.Strings(subs)
+
+	chunks := make([]Chunk, 0, len(subs))
+	for _, sub := range subs {
+		text, err := renderText(grouped[sub])
+		if err != nil {
+			return nil, fmt.Errorf("rendering subsystem %s: %v", sub, err)
+		}
+		chunks = append(chunks, Chunk{Subsystem: sub, Text: text})
+	}
+	return chunks, nil
+}
+
+// renderText serializes metric families back to Prometheus text format.
+func renderText(families []*dto.MetricFamily) (string, error) {
+	var buf bytes.Buffer
+	for _, fam := range families {
+		if _, err := expfmt.MetricFamilyToText(&buf, fam); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
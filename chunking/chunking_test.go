@@ -0,0 +1,81 @@
+package chunking
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{"vault_core_unsealed", "core"},
+		{"vault_runtime_alloc_bytes", "runtime"},
+		{"vault_expire_num_leases", "expire"},
+		{"vault_token_creation", "token"},
+		{"vault_barrier_put_count", "storage"},
+		{"vault_storage_latency", "storage"},
+		{"vault_audit_log_request_count", "audit"},
+		{"vault_ha_rpc_count", "ha"},
+		{"process_cpu_seconds_total", "other"},
+	}
+	for _, c := range cases {
+		if got := classify(c.name); got != c.want {
+			t.Errorf("classify(%q) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	input := `# HELP vault_core_unsealed core unsealed status
+# TYPE vault_core_unsealed gauge
+vault_core_unsealed 1
+# HELP vault_barrier_put_count barrier puts
+# TYPE vault_barrier_put_count counter
+vault_barrier_put_count 42
+# HELP vault_storage_latency storage latency
+# TYPE vault_storage_latency gauge
+vault_storage_latency 0.05
+# HELP process_cpu_seconds_total cpu time
+# TYPE process_cpu_seconds_total counter
+process_cpu_seconds_total 12.3
+`
+	chunks, err := Group([]byte(input))
+	if err != nil {
+		t.Fatalf("Group: %v", err)
+	}
+
+	bySubsystem := make(map[string]Chunk, len(chunks))
+	for _, c := range chunks {
+		bySubsystem[c.Subsystem] = c
+	}
+
+	if _, ok := bySubsystem["core"]; !ok {
+		t.Errorf("expected a core chunk, got subsystems %v", subsystemNames(chunks))
+	}
+	storage, ok := bySubsystem["storage"]
+	if !ok {
+		t.Fatalf("expected a storage chunk, got subsystems %v", subsystemNames(chunks))
+	}
+	if !strings.Contains(storage.Text, "vault_barrier_put_count") || !strings.Contains(storage.Text, "vault_storage_latency") {
+		t.Errorf("storage chunk should merge both vault_barrier_ and vault_storage_ metrics, got:\n%s", storage.Text)
+	}
+	if _, ok := bySubsystem["other"]; !ok {
+		t.Errorf("expected an other chunk for process_cpu_seconds_total, got subsystems %v", subsystemNames(chunks))
+	}
+
+	for i := 1; i < len(chunks); i++ {
+		if chunks[i-1].Subsystem > chunks[i].Subsystem {
+			t.Errorf("chunks not sorted: %q came before %q", chunks[i-1].Subsystem, chunks[i].Subsystem)
+		}
+	}
+}
+
+func subsystemNames(chunks []Chunk) []string {
+	names := make([]string, len(chunks))
+	for i, c := range chunks {
+		names[i] = c.Subsystem
+	}
+	return names
+}
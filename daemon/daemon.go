@@ -0,0 +1,124 @@
+// Package daemon turns the one-shot CLI into a long-running service: it
+// periodically re-runs the scrape/analyze pipeline on a timer and
+// serves the latest result over HTTP, alongside its own Prometheus
+// metrics describing the analysis pipeline itself.
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/infa-amohanda/vault-metrics-ai/report"
+)
+
+// Per-cycle error counts are tracked by obs.LLMErrorsTotal instead of a
+// counter local to this package, since only the analyze step (in main)
+// knows which LLM provider was in use and why it failed.
+var (
+	llmRequestsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "vault_metrics_ai_llm_requests_total",
+		Help: "Total number of scrape/analyze cycles run by the daemon.",
+	})
+	llmLatencySeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "vault_metrics_ai_llm_latency_seconds",
+		Help:    "Latency of a full scrape/analyze cycle, in seconds.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+// AnalyzeFunc performs one scrape-and-analyze cycle, returning both the
+// human-readable text analysis and the structured report.
+type AnalyzeFunc func() (string, report.AnalysisReport, error)
+
+// Server runs the periodic scrape/analyze loop and exposes the most
+// recent result over HTTP.
+type Server struct {
+	analyze  AnalyzeFunc
+	interval time.Duration
+
+	mu      sync.RWMutex
+	text    string
+	rep     report.AnalysisReport
+	lastErr error
+	lastRun time.Time
+}
+
+// NewServer constructs a Server that calls analyze once immediately and
+// then every interval thereafter once Run is called.
+func NewServer(analyze AnalyzeFunc, interval time.Duration) *Server {
+	return &Server{analyze: analyze, interval: interval}
+}
+
+// Run starts the scrape scheduler. It blocks forever, so callers
+// typically invoke it with "go srv.Run()".
+func (s *Server) Run() {
+	s.runOnce()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.runOnce()
+	}
+}
+
+func (s *Server) runOnce() {
+	start := time.Now()
+	text, rep, err := s.analyze()
+	llmRequestsTotal.Inc()
+	llmLatencySeconds.Observe(time.Since(start).Seconds())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRun = time.Now()
+	s.lastErr = err
+	if err == nil {
+		s.text = text
+		s.rep = rep
+	}
+}
+
+// Handler returns the HTTP handler serving /analysis, /analysis.txt,
+// /healthz and /metrics.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/analysis", s.handleAnalysisJSON)
+	mux.HandleFunc("/analysis.txt", s.handleAnalysisText)
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
+
+func (s *Server) handleAnalysisJSON(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.rep)
+}
+
+func (s *Server) handleAnalysisText(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fmt.Fprint(w, s.text)
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.lastRun.IsZero() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, "no analysis has completed yet")
+		return
+	}
+	if s.lastErr != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, "last cycle failed:", s.lastErr)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// anthropicProvider talks to the Anthropic Messages API.
+type anthropicProvider struct {
+	url   string
+	token string
+	model string
+}
+
+func (p *anthropicProvider) Generate(ctx context.Context, prompt string, opts Options) (string, error) {
+	model := p.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+	maxTokens := opts.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 4096
+	}
+	payload := map[string]interface{}{
+		"model":       model,
+		"max_tokens":  maxTokens,
+		"temperature": opts.Temperature,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	body, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	if p.token == "" {
+		return "", fmt.Errorf("LLM_TOKEN environment variable not set")
+	}
+	req.Header.Set("x-api-key", p.token)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	result, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("LLM API error: %s\n%s", resp.Status, string(result))
+	}
+
+	var llmResp struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(result, &llmResp); err == nil {
+		var out bytes.Buffer
+		for _, c := range llmResp.Content {
+			out.WriteString(c.Text)
+		}
+		if out.Len() > 0 {
+			return out.String(), nil
+		}
+	}
+	return "", ErrEmptyResponse
+}
@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+)
+
+// Config selects an LLM provider and holds its connection settings. It
+// is resolved from an optional JSON config file with environment
+// variables taking precedence, so a deployment can ship a base config
+// file and override individual fields per-environment.
+type Config struct {
+	Provider    string  `json:"provider"`
+	URL         string  `json:"url"`
+	Token       string  `json:"token"`
+	Model       string  `json:"model"`
+	MaxTokens   int     `json:"max_tokens"`
+	Temperature float64 `json:"temperature"`
+}
+
+// LoadConfig resolves the provider config from LLM_CONFIG_FILE (if set),
+// then overlays LLM_PROVIDER, LLM_URL, LLM_TOKEN, LLM_MODEL and
+// LLM_TEMPERATURE.
+func LoadConfig() (Config, error) {
+	cfg := Config{
+		Provider:  "openai",
+		Model:     "qwen2.5-32b-instruct",
+		MaxTokens: 20000,
+	}
+
+	if path := os.Getenv("LLM_CONFIG_FILE"); path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("reading LLM config file: %v", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("parsing LLM config file: %v", err)
+		}
+	}
+
+	if v := os.Getenv("LLM_PROVIDER"); v != "" {
+		cfg.Provider = v
+	}
+	if v := os.Getenv("LLM_URL"); v != "" {
+		cfg.URL = v
+	}
+	if v := os.Getenv("LLM_TOKEN"); v != "" {
+		cfg.Token = v
+	}
+	if v := os.Getenv("LLM_MODEL"); v != "" {
+		cfg.Model = v
+	}
+	if v := os.Getenv("LLM_TEMPERATURE"); v != "" {
+		temp, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return cfg, fmt.Errorf("parsing LLM_TEMPERATURE: %v", err)
+		}
+		cfg.Temperature = temp
+	}
+
+	return cfg, nil
+}
+
+// New constructs the Provider named by cfg.Provider.
+func New(cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case "", "openai":
+		return &openAIProvider{url: cfg.URL, token: cfg.Token, model: cfg.Model}, nil
+	case "anthropic":
+		return &anthropicProvider{url: cfg.URL, token: cfg.Token, model: cfg.Model}, nil
+	case "ollama":
+		return &ollamaProvider{url: cfg.URL, model: cfg.Model}, nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q (want openai, anthropic or ollama)", cfg.Provider)
+	}
+}
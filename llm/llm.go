@@ -0,0 +1,31 @@
+// Package llm provides a pluggable interface over the various LLM
+// backends this tool can send Vault metrics to for analysis.
+package llm
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrEmptyResponse is returned by a Provider's Generate when the backend
+// responded successfully but no text content could be extracted from
+// it, so callers don't mistake an empty/unparseable response for a
+// meaningful analysis.
+var ErrEmptyResponse = errors.New("llm: provider returned no text content")
+
+// Options controls a single Generate call. An empty Model falls back to
+// the Provider's configured model, and a zero MaxTokens makes the
+// Anthropic provider send 4096. The OpenAI provider sends MaxTokens as
+// given and the Ollama provider does not send it; every provider sends
+// Temperature as given, zero included.
+type Options struct {
+	Model       string
+	MaxTokens   int
+	Temperature float64
+}
+
+// Provider is implemented by each supported LLM backend. Generate sends
+// prompt to the backend and returns the completion text.
+type Provider interface {
+	Generate(ctx context.Context, prompt string, opts Options) (string, error)
+}
@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ollamaProvider talks to Ollama's /api/generate endpoint, which streams
+// back newline-delimited JSON objects rather than a single response body.
+type ollamaProvider struct {
+	url   string
+	model string
+}
+
+func (p *ollamaProvider) Generate(ctx context.Context, prompt string, opts Options) (string, error) {
+	model := p.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+	payload := map[string]interface{}{
+		"model":  model,
+		"prompt": prompt,
+		"stream": true,
+		"options": map[string]interface{}{
+			"temperature": opts.Temperature,
+		},
+	}
+	body, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("LLM API error: %s", resp.Status)
+	}
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var chunk struct {
+			Response string `json:"response"`
+			Done     bool   `json:"done"`
+		}
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return "", fmt.Errorf("parsing ollama NDJSON chunk: %v", err)
+		}
+		out.WriteString(chunk.Response)
+		if chunk.Done {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	if out.Len() == 0 {
+		return "", ErrEmptyResponse
+	}
+	return out.String(), nil
+}
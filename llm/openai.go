@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// openAIProvider talks to any OpenAI-compatible completions/chat API.
+type openAIProvider struct {
+	url   string
+	token string
+	model string
+}
+
+func (p *openAIProvider) Generate(ctx context.Context, prompt string, opts Options) (string, error) {
+	model := p.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+	payload := map[string]interface{}{
+		"model":       model,
+		"prompt":      prompt,
+		"max_tokens":  opts.MaxTokens,
+		"temperature": opts.Temperature,
+	}
+	body, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	if p.token == "" {
+		return "", fmt.Errorf("LLM_TOKEN environment variable not set")
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	result, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("LLM API error: %s\n%s", resp.Status, string(result))
+	}
+
+	var llmResp struct {
+		Choices []struct {
+			Text    string `json:"text"`
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		// fallback for other possible fields
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(result, &llmResp); err == nil {
+		if len(llmResp.Choices) > 0 {
+			if llmResp.Choices[0].Text != "" {
+				return llmResp.Choices[0].Text, nil
+			}
+			if llmResp.Choices[0].Message.Content != "" {
+				return llmResp.Choices[0].Message.Content, nil
+			}
+		}
+		if llmResp.Result != "" {
+			return llmResp.Result, nil
+		}
+	}
+	return "", ErrEmptyResponse
+}
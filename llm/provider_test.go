@@ -0,0 +1,192 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOpenAIProviderGenerate(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "choices text",
+			body: `{"choices":[{"text":"hello from completions"}]}`,
+			want: "hello from completions",
+		},
+		{
+			name: "choices message content",
+			body: `{"choices":[{"message":{"content":"hello from chat"}}]}`,
+			want: "hello from chat",
+		},
+		{
+			name: "fallback result field",
+			body: `{"result":"hello from result"}`,
+			want: "hello from result",
+		},
+		{
+			name:    "no recognizable text field",
+			body:    `{"id":"abc123"}`,
+			wantErr: ErrEmptyResponse,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := newTestServer(t, http.StatusOK, c.body)
+			p := &openAIProvider{url: srv.URL, token: "test-token", model: "test-model"}
+
+			got, err := p.Generate(context.Background(), "prompt", Options{})
+			if c.wantErr != nil {
+				if !errors.Is(err, c.wantErr) {
+					t.Fatalf("Generate() error = %v, want %v", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate(): %v", err)
+			}
+			if got != c.want {
+				t.Errorf("Generate() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIProviderRequiresToken(t *testing.T) {
+	p := &openAIProvider{url: "http://example.invalid", model: "test-model"}
+	if _, err := p.Generate(context.Background(), "prompt", Options{}); err == nil {
+		t.Fatal("expected an error when no token is configured, got nil")
+	}
+}
+
+func TestOpenAIProviderHTTPError(t *testing.T) {
+	srv := newTestServer(t, http.StatusInternalServerError, "boom")
+	p := &openAIProvider{url: srv.URL, token: "test-token", model: "test-model"}
+	if _, err := p.Generate(context.Background(), "prompt", Options{}); err == nil {
+		t.Fatal("expected an error on a non-200 response, got nil")
+	}
+}
+
+func TestAnthropicProviderGenerate(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "single content block",
+			body: `{"content":[{"text":"hello from claude"}]}`,
+			want: "hello from claude",
+		},
+		{
+			name: "multiple content blocks are concatenated",
+			body: `{"content":[{"text":"hello "},{"text":"world"}]}`,
+			want: "hello world",
+		},
+		{
+			name:    "no content blocks",
+			body:    `{"content":[]}`,
+			wantErr: ErrEmptyResponse,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := newTestServer(t, http.StatusOK, c.body)
+			p := &anthropicProvider{url: srv.URL, token: "test-token", model: "test-model"}
+
+			got, err := p.Generate(context.Background(), "prompt", Options{})
+			if c.wantErr != nil {
+				if !errors.Is(err, c.wantErr) {
+					t.Fatalf("Generate() error = %v, want %v", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate(): %v", err)
+			}
+			if got != c.want {
+				t.Errorf("Generate() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestAnthropicProviderRequiresToken(t *testing.T) {
+	p := &anthropicProvider{url: "http://example.invalid", model: "test-model"}
+	if _, err := p.Generate(context.Background(), "prompt", Options{}); err == nil {
+		t.Fatal("expected an error when no token is configured, got nil")
+	}
+}
+
+func TestOllamaProviderGenerate(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "single chunk",
+			body: `{"response":"hello from ollama","done":true}` + "\n",
+			want: "hello from ollama",
+		},
+		{
+			name: "streamed chunks are concatenated",
+			body: `{"response":"hello ","done":false}` + "\n" + `{"response":"world","done":true}` + "\n",
+			want: "hello world",
+		},
+		{
+			name:    "empty stream",
+			body:    `{"response":"","done":true}` + "\n",
+			wantErr: ErrEmptyResponse,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := newTestServer(t, http.StatusOK, c.body)
+			p := &ollamaProvider{url: srv.URL, model: "test-model"}
+
+			got, err := p.Generate(context.Background(), "prompt", Options{})
+			if c.wantErr != nil {
+				if !errors.Is(err, c.wantErr) {
+					t.Fatalf("Generate() error = %v, want %v", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate(): %v", err)
+			}
+			if got != c.want {
+				t.Errorf("Generate() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestOllamaProviderMalformedChunk(t *testing.T) {
+	srv := newTestServer(t, http.StatusOK, "not json\n")
+	p := &ollamaProvider{url: srv.URL, model: "test-model"}
+	if _, err := p.Generate(context.Background(), "prompt", Options{}); err == nil {
+		t.Fatal("expected an error on a malformed NDJSON chunk, got nil")
+	}
+}
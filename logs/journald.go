@@ -0,0 +1,76 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// JournaldSource reads journald entries for the Vault service unit via
+// journalctl.
+type JournaldSource struct {
+	Unit string // defaults to "vault.service" if empty
+}
+
+func (s *JournaldSource) Fetch(ctx context.Context, w Window) ([]Entry, error) {
+	unit := s.Unit
+	if unit == "" {
+		unit = "vault.service"
+	}
+
+	cmd := exec.CommandContext(ctx, "journalctl",
+		"-u", unit,
+		"--output=json",
+		"--since", w.From.Format("2006-01-02 15:04:05"),
+		"--until", w.To.Format("2006-01-02 15:04:05"),
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running journalctl for unit %s: %v", unit, err)
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var raw struct {
+			RealtimeTimestamp string `json:"__REALTIME_TIMESTAMP"`
+			Message           string `json:"MESSAGE"`
+			Priority          string `json:"PRIORITY"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &raw); err != nil {
+			continue
+		}
+		micros, err := strconv.ParseInt(raw.RealtimeTimestamp, 10, 64)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, redact(Entry{
+			Timestamp: time.UnixMicro(micros),
+			Level:     journaldLevel(raw.Priority),
+			Message:   raw.Message,
+		}))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// journaldLevel maps a syslog priority (0-7, most to least severe) to
+// the same level vocabulary as the other log sources.
+func journaldLevel(priority string) string {
+	switch priority {
+	case "0", "1", "2", "3":
+		return "error"
+	case "4":
+		return "warn"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,98 @@
+// Package logs supplies a second evidence source - Vault audit and
+// operational log entries - that analyzeWithLLM can cross-reference
+// against metric anomalies, selected via --logs-source.
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is one log line, normalized across backends.
+type Entry struct {
+	Timestamp time.Time
+	Level     string // e.g. "info", "warn", "error"
+	Message   string
+	Fields    map[string]interface{}
+}
+
+// Window bounds the time range of interest, typically the scrape
+// interval, so log entries can be correlated with the metrics they
+// accompany.
+type Window struct {
+	From, To time.Time
+}
+
+// Source fetches log entries for a time window.
+type Source interface {
+	Fetch(ctx context.Context, w Window) ([]Entry, error)
+}
+
+// NewSource constructs the Source named by kind. location is
+// backend-specific: a file path for "file", a base URL for "loki", or a
+// unit name for "journald" (defaulting to "vault.service" if empty).
+func NewSource(kind, location string) (Source, error) {
+	switch kind {
+	case "", "none":
+		return nil, nil
+	case "file":
+		return &FileSource{Path: location}, nil
+	case "loki":
+		return &LokiSource{URL: location}, nil
+	case "journald":
+		return &JournaldSource{Unit: location}, nil
+	default:
+		return nil, fmt.Errorf("unknown logs source %q (want file, loki or journald)", kind)
+	}
+}
+
+// TopN returns the n most severe entries (errors before warnings before
+// everything else, ties broken by recency).
+func TopN(entries []Entry, n int) []Entry {
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool {
+		si, sj := severityRank(sorted[i].Level), severityRank(sorted[j].Level)
+		if si != sj {
+			return si > sj
+		}
+		return sorted[i].Timestamp.After(sorted[j].Timestamp)
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+func severityRank(level string) int {
+	switch strings.ToLower(level) {
+	case "error", "err":
+		return 2
+	case "warn", "warning":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Render formats entries as plain text for inclusion in the LLM prompt.
+// Fields is included (already redacted by the Source that produced the
+// entry) since it's often where the evidence cross-referenced against a
+// metric anomaly actually lives.
+func Render(entries []Entry) []byte {
+	var buf strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "[%s] %s: %s", e.Timestamp.Format(time.RFC3339), e.Level, e.Message)
+		if len(e.Fields) > 0 {
+			if data, err := json.Marshal(e.Fields); err == nil {
+				fmt.Fprintf(&buf, " %s", data)
+			}
+		}
+		buf.WriteString("\n")
+	}
+	return []byte(buf.String())
+}
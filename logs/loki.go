@@ -0,0 +1,70 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// LokiSource queries a Loki (or Loki-compatible Elasticsearch) endpoint
+// for log lines from the Vault job over a time window.
+type LokiSource struct {
+	URL string
+}
+
+func (s *LokiSource) Fetch(ctx context.Context, w Window) ([]Entry, error) {
+	query := `{job="vault"}`
+	reqURL := fmt.Sprintf("%s/loki/api/v1/query_range?query=%s&start=%d&end=%d",
+		s.URL, url.QueryEscape(query), w.From.UnixNano(), w.To.UnixNano())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("loki query error: %s\n%s", resp.Status, string(body))
+	}
+
+	var lokiResp struct {
+		Data struct {
+			Result []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string        `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &lokiResp); err != nil {
+		return nil, fmt.Errorf("parsing loki response: %v", err)
+	}
+
+	var entries []Entry
+	for _, stream := range lokiResp.Data.Result {
+		level := stream.Stream["level"]
+		for _, v := range stream.Values {
+			nanos, err := strconv.ParseInt(v[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			entries = append(entries, redact(Entry{
+				Timestamp: time.Unix(0, nanos),
+				Level:     level,
+				Message:   v[1],
+			}))
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,50 @@
+package logs
+
+import "strings"
+
+// redactedFields are scrubbed from an entry's Fields before it is sent
+// to the LLM, using dotted paths into nested objects (as Vault audit log
+// entries use, e.g. {"auth": {"client_token": "..."}}).
+var redactedFields = []string{
+	"auth.client_token",
+	"request.data",
+}
+
+func redact(e Entry) Entry {
+	if e.Fields == nil {
+		return e
+	}
+	fields := deepCopy(e.Fields)
+	for _, path := range redactedFields {
+		redactPath(fields, path)
+	}
+	e.Fields = fields
+	return e
+}
+
+func redactPath(fields map[string]interface{}, path string) {
+	parts := strings.SplitN(path, ".", 2)
+	if len(parts) == 1 {
+		if _, ok := fields[parts[0]]; ok {
+			fields[parts[0]] = "[REDACTED]"
+		}
+		return
+	}
+	nested, ok := fields[parts[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	redactPath(nested, parts[1])
+}
+
+func deepCopy(fields map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if nested, ok := v.(map[string]interface{}); ok {
+			out[k] = deepCopy(nested)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
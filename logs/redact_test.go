@@ -0,0 +1,63 @@
+package logs
+
+import "testing"
+
+func TestRedactPathNested(t *testing.T) {
+	fields := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token": "s.abc123",
+			"accessor":     "keep-me",
+		},
+	}
+	redactPath(fields, "auth.client_token")
+
+	auth := fields["auth"].(map[string]interface{})
+	if auth["client_token"] != "[REDACTED]" {
+		t.Errorf("client_token = %v, want [REDACTED]", auth["client_token"])
+	}
+	if auth["accessor"] != "keep-me" {
+		t.Errorf("accessor was modified: %v", auth["accessor"])
+	}
+}
+
+func TestRedactPathMissing(t *testing.T) {
+	fields := map[string]interface{}{"request": map[string]interface{}{}}
+	redactPath(fields, "request.data")
+
+	request := fields["request"].(map[string]interface{})
+	if _, ok := request["data"]; ok {
+		t.Errorf("redactPath should not create a key that wasn't present, got %v", request)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	e := Entry{
+		Level:   "error",
+		Message: "permission denied",
+		Fields: map[string]interface{}{
+			"auth":    map[string]interface{}{"client_token": "s.abc123"},
+			"request": map[string]interface{}{"data": map[string]interface{}{"password": "hunter2"}},
+			"path":    "secret/data/foo",
+		},
+	}
+
+	redacted := redact(e)
+
+	auth := redacted.Fields["auth"].(map[string]interface{})
+	if auth["client_token"] != "[REDACTED]" {
+		t.Errorf("auth.client_token = %v, want [REDACTED]", auth["client_token"])
+	}
+	request := redacted.Fields["request"].(map[string]interface{})
+	if request["data"] != "[REDACTED]" {
+		t.Errorf("request.data = %v, want [REDACTED]", request["data"])
+	}
+	if redacted.Fields["path"] != "secret/data/foo" {
+		t.Errorf("path was modified: %v", redacted.Fields["path"])
+	}
+
+	// The original entry's Fields must be untouched.
+	origAuth := e.Fields["auth"].(map[string]interface{})
+	if origAuth["client_token"] != "s.abc123" {
+		t.Errorf("redact mutated the original entry's Fields: %v", origAuth["client_token"])
+	}
+}
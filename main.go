@@ -2,13 +2,55 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"time"
+
+	"github.com/infa-amohanda/vault-metrics-ai/chunking"
+	"github.com/infa-amohanda/vault-metrics-ai/daemon"
+	"github.com/infa-amohanda/vault-metrics-ai/llm"
+	vaultlogs "github.com/infa-amohanda/vault-metrics-ai/logs"
+	"github.com/infa-amohanda/vault-metrics-ai/obs"
+	"github.com/infa-amohanda/vault-metrics-ai/report"
+	"github.com/infa-amohanda/vault-metrics-ai/store"
 )
 
+var format = flag.String("format", "text", "analysis output format: text or json")
+var logsSource = flag.String("logs-source", "", "log evidence source to cross-reference with metrics: file, loki, journald, or empty to disable")
+var logsLocation = flag.String("logs-location", "", "location for --logs-source: a file path (file), base URL (loki), or unit name (journald)")
+var baseline = flag.String("baseline", "", "RFC3339 timestamp of a known-good snapshot to compare against, instead of the previous scrape (requires VAULT_METRICS_STORE_PATH)")
+
+const logsWindow = 5 * time.Minute
+const logsTopN = 20
+
+// fetchLogs retrieves and redacts the most severe log entries from the
+// configured --logs-source within the recent scrape window, for
+// correlation with metric anomalies. It returns nil if no logs source is
+// configured, or if the fetch fails.
+func fetchLogs() []byte {
+	src, err := vaultlogs.NewSource(*logsSource, *logsLocation)
+	if err != nil {
+		fmt.Println("Error configuring logs source:", err)
+		return nil
+	}
+	if src == nil {
+		return nil
+	}
+
+	now := time.Now()
+	entries, err := src.Fetch(context.Background(), vaultlogs.Window{From: now.Add(-logsWindow), To: now})
+	if err != nil {
+		fmt.Println("Error fetching logs:", err)
+		return nil
+	}
+	return vaultlogs.Render(vaultlogs.TopN(entries, logsTopN))
+}
+
 func getVaultMetricsURL() string {
 	vaultAddr := os.Getenv("VAULT_ADDR")
 	if vaultAddr == "" {
@@ -17,15 +59,6 @@ func getVaultMetricsURL() string {
 	return vaultAddr + "/v1/sys/metrics?format=prometheus"
 }
 
-func getLLMAPIURL() string {
-	llmURL := os.Getenv("LLM_URL")
-	if llmURL == "" {
-		fmt.Fprintln(os.Stderr, "LLM_URL environment variable not set")
-		os.Exit(1)
-	}
-	return llmURL
-}
-
 func fetchVaultMetrics() ([]byte, error) {
 	vaultToken := os.Getenv("VAULT_TOKEN")
 	if vaultToken == "" {
@@ -51,77 +84,367 @@ func filterNaNMetrics(metrics []byte) []byte {
 	lines := bytes.Split(metrics, []byte("\n"))
 	var filtered [][]byte
 	for _, line := range lines {
-		if !bytes.Contains(line, []byte("NaN")) {
-			filtered = append(filtered, line)
+		if bytes.Contains(line, []byte("NaN")) {
+			obs.NaNFilteredTotal.Inc()
+			continue
 		}
+		filtered = append(filtered, line)
 	}
 	return bytes.Join(filtered, []byte("\n"))
 }
 
+// analyzeWithLLM asks the configured LLM provider (see the llm package,
+// selected via LLM_PROVIDER/LLM_CONFIG_FILE) to summarize metrics for a
+// human reader. logs, if non-nil, is top-N audit/operational log
+// evidence from --logs-source (see fetchLogs) for the LLM to
+// cross-reference against metric anomalies.
 func analyzeWithLLM(metrics, logs []byte) (string, error) {
-	llmPrompt := fmt.Sprintf(`Analyze the following Vault server metrics for a human reader.
+	cfg, err := llm.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	provider, err := llm.New(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	prompt := fmt.Sprintf(`Analyze the following Vault server metrics for a human reader.
 - Summarize the overall health and status in clear, simple language.
 - Highlight any anomalies, errors, or warnings.
 - Suggest possible causes and recommended actions if issues are found.
 - Make the summary concise, actionable, and easy to understand for someone without deep technical knowledge.
 
 Metrics:
-%s`, string(metrics))
-	payload := map[string]interface{}{
-		"model":      "qwen2.5-32b-instruct",
-		"prompt":     llmPrompt,
-		"max_tokens": 20000,
-		"metadata": map[string]string{
-			"trace_name": "team_bot",
-		},
+%s%s`, string(metrics), logsSection(logs))
+
+	return provider.Generate(context.Background(), prompt, llm.Options{
+		Model:       cfg.Model,
+		MaxTokens:   cfg.MaxTokens,
+		Temperature: cfg.Temperature,
+	})
+}
+
+// logsSection renders logs as a prompt section cross-referencing log
+// evidence against the metrics above it, or "" if there are no logs.
+func logsSection(logs []byte) string {
+	if len(logs) == 0 {
+		return ""
 	}
-	body, _ := json.Marshal(payload)
+	return fmt.Sprintf("\n\nRecent error/warning log entries from the same window, for cross-reference:\n%s", string(logs))
+}
 
-	req, err := http.NewRequest("POST", getLLMAPIURL(), bytes.NewBuffer(body))
+// reduceAnalyses asks the configured LLM provider to summarize several
+// per-subsystem analyses into a single report, preserving the cross-
+// subsystem picture that analyzing each chunk in isolation would miss.
+func reduceAnalyses(analyses []chunking.Chunk, logs []byte) (string, error) {
+	cfg, err := llm.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	provider, err := llm.New(cfg)
 	if err != nil {
 		return "", err
 	}
-	llmToken := os.Getenv("LLM_TOKEN")
-	if llmToken == "" {
-		return "", fmt.Errorf("LLM_TOKEN environment variable not set")
+
+	var perSubsystem bytes.Buffer
+	for _, a := range analyses {
+		fmt.Fprintf(&perSubsystem, "## %s\n%s\n\n", a.Subsystem, a.Text)
 	}
-	req.Header.Set("Authorization", "Bearer "+llmToken)
-	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	prompt := fmt.Sprintf(`The following are independent analyses of different Vault subsystems from the same scrape. Summarize them into a single, coherent overall health report for a human reader, calling out anything that spans multiple subsystems.
+
+%s%s`, perSubsystem.String(), logsSection(logs))
+
+	return provider.Generate(context.Background(), prompt, llm.Options{
+		Model:       cfg.Model,
+		MaxTokens:   cfg.MaxTokens,
+		Temperature: cfg.Temperature,
+	})
+}
+
+// reduceStructuredAnalyses is the structured counterpart to
+// reduceAnalyses: it asks the configured LLM provider to merge several
+// per-subsystem structured reports into a single report.AnalysisReport,
+// retrying once with the parse error appended if the merged response
+// isn't valid JSON.
+func reduceStructuredAnalyses(analyses []chunking.Chunk, logs []byte) (report.AnalysisReport, error) {
+	cfg, err := llm.LoadConfig()
 	if err != nil {
-		return "", err
+		return report.AnalysisReport{}, err
+	}
+	provider, err := llm.New(cfg)
+	if err != nil {
+		return report.AnalysisReport{}, err
+	}
+	opts := llm.Options{Model: cfg.Model, MaxTokens: cfg.MaxTokens, Temperature: cfg.Temperature}
+
+	var perSubsystem bytes.Buffer
+	for _, a := range analyses {
+		fmt.Fprintf(&perSubsystem, "## %s\n%s\n\n", a.Subsystem, a.Text)
 	}
-	defer resp.Body.Close()
-	result, _ := ioutil.ReadAll(resp.Body)
 
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("LLM API error: %s\n%s", resp.Status, string(result))
+	buildPrompt := func(priorError string) string {
+		prompt := fmt.Sprintf(`The following are independent structured analyses of different Vault subsystems from the same scrape. Merge them into a single JSON object matching this schema, no surrounding prose:
+
+%s
+
+Per-subsystem analyses:
+%s%s`, report.Schema, perSubsystem.String(), logsSection(logs))
+		if priorError != "" {
+			prompt += fmt.Sprintf("\n\nYour previous response could not be parsed: %s\nReturn ONLY the corrected JSON object.", priorError)
+		}
+		return prompt
 	}
 
-	// Try to parse the response as JSON and extract the summary text
-	var llmResp struct {
-		Choices []struct {
-			Text string `json:"text"`
-		} `json:"choices"`
-		// fallback for other possible fields
-		Result string `json:"result"`
+	raw, err := provider.Generate(context.Background(), buildPrompt(""), opts)
+	if err != nil {
+		return report.AnalysisReport{}, err
+	}
+	rep, parseErr := report.Parse([]byte(raw))
+	if parseErr == nil {
+		return rep, nil
+	}
+
+	raw, err = provider.Generate(context.Background(), buildPrompt(parseErr.Error()), opts)
+	if err != nil {
+		return report.AnalysisReport{}, err
+	}
+	rep, err = report.Parse([]byte(raw))
+	if err != nil {
+		return report.AnalysisReport{}, fmt.Errorf("LLM returned invalid merged structured report after retry: %v", err)
+	}
+	return rep, nil
+}
+
+// analyzeStructured asks the configured LLM provider to return a
+// report.AnalysisReport as JSON instead of free-form prose. If the LLM
+// returns malformed or invalid JSON, it retries once with the parse
+// error appended to the prompt.
+func analyzeStructured(metrics, logs []byte) (report.AnalysisReport, error) {
+	cfg, err := llm.LoadConfig()
+	if err != nil {
+		return report.AnalysisReport{}, err
 	}
-	err = json.Unmarshal(result, &llmResp)
-	if err == nil {
-		if len(llmResp.Choices) > 0 && llmResp.Choices[0].Text != "" {
-			return llmResp.Choices[0].Text, nil
+	provider, err := llm.New(cfg)
+	if err != nil {
+		return report.AnalysisReport{}, err
+	}
+
+	opts := llm.Options{Model: cfg.Model, MaxTokens: cfg.MaxTokens, Temperature: cfg.Temperature}
+
+	raw, err := provider.Generate(context.Background(), structuredPrompt(metrics, logs, ""), opts)
+	if err != nil {
+		return report.AnalysisReport{}, err
+	}
+	rep, parseErr := report.Parse([]byte(raw))
+	if parseErr == nil {
+		return rep, nil
+	}
+
+	// Retry once, telling the LLM what it got wrong.
+	raw, err = provider.Generate(context.Background(), structuredPrompt(metrics, logs, parseErr.Error()), opts)
+	if err != nil {
+		return report.AnalysisReport{}, err
+	}
+	rep, err = report.Parse([]byte(raw))
+	if err != nil {
+		return report.AnalysisReport{}, fmt.Errorf("LLM returned invalid structured report after retry: %v", err)
+	}
+	return rep, nil
+}
+
+// analyzeStructuredChunked is the structured counterpart to the
+// chunked text-analysis path in main(): it runs analyzeStructured
+// per-subsystem chunk, then merges the per-subsystem reports into a
+// single report.AnalysisReport via reduceStructuredAnalyses.
+func analyzeStructuredChunked(chunks []chunking.Chunk, logs []byte) (report.AnalysisReport, error) {
+	subsystemAnalyses := make([]chunking.Chunk, 0, len(chunks))
+	for _, c := range chunks {
+		fmt.Printf("analysis data for subsystem %q (structured)...\n", c.Subsystem)
+		rep, err := analyzeStructured([]byte(c.Text), nil)
+		if err != nil {
+			return report.AnalysisReport{}, fmt.Errorf("analyzing subsystem %q: %v", c.Subsystem, err)
 		}
-		if llmResp.Result != "" {
-			return llmResp.Result, nil
+		encoded, err := json.Marshal(rep)
+		if err != nil {
+			return report.AnalysisReport{}, fmt.Errorf("encoding subsystem %q report: %v", c.Subsystem, err)
 		}
+		subsystemAnalyses = append(subsystemAnalyses, chunking.Chunk{Subsystem: c.Subsystem, Text: string(encoded)})
+	}
+
+	fmt.Print("reducing per-subsystem structured reports into a single report...\n")
+	return reduceStructuredAnalyses(subsystemAnalyses, logs)
+}
+
+// structuredPrompt builds the prompt for analyzeStructured. If
+// priorError is non-empty, it is appended so the LLM can correct its
+// previous, invalid response.
+func structuredPrompt(metrics, logs []byte, priorError string) string {
+	prompt := fmt.Sprintf(`Analyze the following Vault server metrics and respond with ONLY a single JSON object matching this schema, no surrounding prose:
+
+%s
+
+Metrics:
+%s%s`, report.Schema, string(metrics), logsSection(logs))
+
+	if priorError != "" {
+		prompt += fmt.Sprintf("\n\nYour previous response could not be parsed: %s\nReturn ONLY the corrected JSON object.", priorError)
+	}
+	return prompt
+}
+
+// appendHistory saves metrics to the on-disk store at
+// VAULT_METRICS_STORE_PATH (if set) and appends a summary of how the
+// watched metrics have changed since a previous snapshot. That previous
+// snapshot is the one pinned by --baseline, if set, otherwise the prior
+// scrape once at least two have been recorded. With no store path
+// configured, it returns metrics unchanged.
+func appendHistory(metrics []byte) []byte {
+	path := os.Getenv("VAULT_METRICS_STORE_PATH")
+	if path == "" {
+		return metrics
+	}
+
+	st, err := store.Open(path)
+	if err != nil {
+		fmt.Println("Error opening metrics store:", err)
+		return metrics
+	}
+	defer st.Close()
+
+	snap, err := store.ParseSnapshot(time.Now(), metrics)
+	if err != nil {
+		fmt.Println("Error parsing snapshot for history:", err)
+		return metrics
+	}
+	if err := st.Save(snap); err != nil {
+		fmt.Println("Error saving snapshot to store:", err)
+		return metrics
+	}
+
+	var prev store.Snapshot
+	if *baseline != "" {
+		ts, err := time.Parse(time.RFC3339, *baseline)
+		if err != nil {
+			fmt.Println("Error parsing --baseline timestamp:", err)
+			return metrics
+		}
+		prev, err = st.Baseline(ts)
+		if err != nil {
+			fmt.Println("Error looking up --baseline snapshot:", err)
+			return metrics
+		}
+	} else {
+		recent, err := st.Recent(2)
+		if err != nil || len(recent) < 2 {
+			return metrics
+		}
+		prev = recent[0]
+	}
+	delta := store.CompareWindows(prev, snap)
+
+	var withHistory bytes.Buffer
+	withHistory.Write(metrics)
+	header := "\n\n# Historical deltas since previous scrape:\n"
+	if *baseline != "" {
+		header = fmt.Sprintf("\n\n# Historical deltas since baseline snapshot at %s:\n", prev.Timestamp.Format(time.RFC3339))
+	}
+	withHistory.WriteString(header)
+	withHistory.WriteString(delta.Summarize())
+	return withHistory.Bytes()
+}
+
+// runAnalysisCycle performs one scrape-and-analyze cycle for the "serve"
+// daemon, producing both the free-form text analysis and the structured
+// report so the daemon can serve /analysis.txt and /analysis from a
+// single cycle. Like the one-shot CLI path in main(), it chunks large
+// payloads by subsystem rather than handing the full scrape to the LLM
+// in one request.
+func runAnalysisCycle() (string, report.AnalysisReport, error) {
+	provider := "unknown"
+	if cfg, err := llm.LoadConfig(); err == nil {
+		provider = cfg.Provider
+	}
+
+	metrics, err := fetchVaultMetrics()
+	if err != nil {
+		obs.LLMErrorsTotal.WithLabelValues(provider, "fetch_metrics").Inc()
+		return "", report.AnalysisReport{}, err
+	}
+	obs.LastScrapeTimestampSeconds.Set(float64(time.Now().Unix()))
+
+	filtered := appendHistory(filterNaNMetrics(metrics))
+	logEntries := fetchLogs()
+
+	var chunks []chunking.Chunk
+	if len(filtered) > 5000 {
+		chunks, err = chunking.Group(filtered)
+		if err != nil {
+			obs.LLMErrorsTotal.WithLabelValues(provider, "chunk_metrics").Inc()
+			return "", report.AnalysisReport{}, err
+		}
+	}
+
+	var text string
+	if chunks != nil {
+		subsystemAnalyses := make([]chunking.Chunk, 0, len(chunks))
+		for _, c := range chunks {
+			analysis, err := analyzeWithLLM([]byte(c.Text), nil)
+			if err != nil {
+				obs.LLMErrorsTotal.WithLabelValues(provider, "llm_text").Inc()
+				return "", report.AnalysisReport{}, err
+			}
+			subsystemAnalyses = append(subsystemAnalyses, chunking.Chunk{Subsystem: c.Subsystem, Text: analysis})
+		}
+		text, err = reduceAnalyses(subsystemAnalyses, logEntries)
+	} else {
+		text, err = analyzeWithLLM(filtered, logEntries)
+	}
+	if err != nil {
+		obs.LLMErrorsTotal.WithLabelValues(provider, "llm_text").Inc()
+		return "", report.AnalysisReport{}, err
+	}
+
+	var rep report.AnalysisReport
+	if chunks != nil {
+		rep, err = analyzeStructuredChunked(chunks, logEntries)
+	} else {
+		rep, err = analyzeStructured(filtered, logEntries)
+	}
+	if err != nil {
+		obs.LLMErrorsTotal.WithLabelValues(provider, "llm_structured").Inc()
+		return text, report.AnalysisReport{}, err
+	}
+	return text, rep, nil
+}
+
+// runServe runs the "serve" subcommand: a long-running daemon that
+// scrapes Vault and re-analyzes it on a timer, serving the latest result
+// over HTTP.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	listenAddr := fs.String("listen-addr", ":9201", "address to serve /analysis, /healthz and /metrics on")
+	scrapeInterval := fs.Duration("scrape-interval", 60*time.Second, "how often to scrape Vault and re-run the LLM analysis")
+	fs.Parse(args)
+
+	srv := daemon.NewServer(runAnalysisCycle, *scrapeInterval)
+	go srv.Run()
+
+	fmt.Printf("serving on %s (scrape interval %s)\n", *listenAddr, *scrapeInterval)
+	if err := http.ListenAndServe(*listenAddr, srv.Handler()); err != nil {
+		fmt.Println("Error serving:", err)
+		os.Exit(1)
 	}
-	// fallback: return raw response
-	return string(result), nil
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+
+	flag.Parse()
+
 	metrics, err := fetchVaultMetrics()
 	fmt.Print("Fetching Vault metrics...\n")
 	if err != nil {
@@ -129,37 +452,64 @@ func main() {
 		os.Exit(1)
 	}
 
-	filteredMetrics := filterNaNMetrics(metrics)
+	filteredMetrics := appendHistory(filterNaNMetrics(metrics))
 	fmt.Println("len(filteredMetrics):", len(filteredMetrics))
+	logEntries := fetchLogs()
+
+	var chunks []chunking.Chunk
 	if len(filteredMetrics) > 5000 {
-		// Split metrics into two halves by lines
-		lines := bytes.Split(filteredMetrics, []byte("\n"))
-		n := len(lines)
-		mid := n / 2
-		firstHalf := bytes.Join(lines[:mid], []byte("\n"))
-		secondHalf := bytes.Join(lines[mid:], []byte("\n"))
-
-		fmt.Print("analysis data for first half...\n")
-		analysis1, err := analyzeWithLLM(firstHalf, nil)
+		chunks, err = chunking.Group(filteredMetrics)
+		if err != nil {
+			fmt.Println("Error parsing metrics for chunking:", err)
+			os.Exit(1)
+		}
+	}
+
+	if *format == "json" {
+		var rep report.AnalysisReport
+		var err error
+		if chunks != nil {
+			rep, err = analyzeStructuredChunked(chunks, logEntries)
+		} else {
+			rep, err = analyzeStructured(filteredMetrics, logEntries)
+		}
+		if err != nil {
+			fmt.Println("Error analyzing metrics with LLM:", err)
+			os.Exit(1)
+		}
+		out, err := json.MarshalIndent(rep, "", "  ")
 		if err != nil {
-			fmt.Println("Error analyzing first half with LLM:", err)
+			fmt.Println("Error marshaling analysis report:", err)
 			os.Exit(1)
 		}
+		fmt.Println(string(out))
+		return
+	}
+
+	if chunks != nil {
+		subsystemAnalyses := make([]chunking.Chunk, 0, len(chunks))
+		for _, c := range chunks {
+			fmt.Printf("analysis data for subsystem %q...\n", c.Subsystem)
+			analysis, err := analyzeWithLLM([]byte(c.Text), nil)
+			if err != nil {
+				fmt.Printf("Error analyzing subsystem %q with LLM: %v\n", c.Subsystem, err)
+				os.Exit(1)
+			}
+			subsystemAnalyses = append(subsystemAnalyses, chunking.Chunk{Subsystem: c.Subsystem, Text: analysis})
+		}
 
-		fmt.Print("analysis data for second half...\n")
-		analysis2, err := analyzeWithLLM(secondHalf, nil)
+		fmt.Print("reducing per-subsystem analyses into a single report...\n")
+		summary, err := reduceAnalyses(subsystemAnalyses, logEntries)
 		if err != nil {
-			fmt.Println("Error analyzing second half with LLM:", err)
+			fmt.Println("Error reducing subsystem analyses with LLM:", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("LLM Analysis Result (First Half):")
-		fmt.Println(analysis1)
-		fmt.Println("\nLLM Analysis Result (Second Half):")
-		fmt.Println(analysis2)
+		fmt.Println("LLM Analysis Result:")
+		fmt.Println(summary)
 	} else {
 		fmt.Print("analysis data for all metrics...\n")
-		analysis, err := analyzeWithLLM(filteredMetrics, nil)
+		analysis, err := analyzeWithLLM(filteredMetrics, logEntries)
 		if err != nil {
 			fmt.Println("Error analyzing metrics with LLM:", err)
 			os.Exit(1)
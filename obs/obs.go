@@ -0,0 +1,34 @@
+// Package obs holds the Prometheus collectors this tool publishes about
+// its own operation - as opposed to the Vault metrics it analyzes -
+// registered on the default registry so they show up at /metrics
+// regardless of which package records them.
+package obs
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// NaNFilteredTotal counts metric lines filterNaNMetrics drops for
+// containing a NaN sample, so operators can see how much of each scrape
+// never reaches the LLM.
+var NaNFilteredTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "vault_metrics_ai_nan_filtered_total",
+	Help: "Total number of Prometheus metric lines dropped for containing NaN.",
+})
+
+// LLMErrorsTotal counts failed scrape/analyze cycles, labeled by the LLM
+// provider in use and a short reason so operators can tell a bad Vault
+// scrape apart from a misbehaving LLM backend.
+var LLMErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "vault_metrics_ai_llm_errors_total",
+	Help: "Total number of scrape/analyze cycles that failed, by provider and reason.",
+}, []string{"provider", "reason"})
+
+// LastScrapeTimestampSeconds records when Vault metrics were last
+// scraped successfully, so operators can alert on a stalled daemon even
+// if it's still answering /healthz.
+var LastScrapeTimestampSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "vault_metrics_ai_last_scrape_timestamp_seconds",
+	Help: "Unix timestamp of the last successful Vault metrics scrape.",
+})
@@ -0,0 +1,98 @@
+// Package report defines the structured analysis output produced in
+// "-format=json" mode, in place of the free-form prose the LLM returns
+// by default.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Health is the overall health verdict for a scrape.
+type Health string
+
+const (
+	HealthOK       Health = "ok"
+	HealthDegraded Health = "degraded"
+	HealthCritical Health = "critical"
+)
+
+// Severity levels for a reported anomaly, from least to most urgent.
+type Severity string
+
+const (
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Anomaly describes a single detected issue in the Vault metrics.
+type Anomaly struct {
+	Metric          string   `json:"metric"`
+	Severity        Severity `json:"severity"`
+	Evidence        string   `json:"evidence"`
+	SuggestedAction string   `json:"suggested_action"`
+}
+
+// AnalysisReport is the structured output the LLM is instructed to
+// return in "-format=json" mode.
+type AnalysisReport struct {
+	OverallHealth     Health    `json:"overall_health"`
+	Anomalies         []Anomaly `json:"anomalies"`
+	SealStatus        string    `json:"seal_status"`
+	HAStatus          string    `json:"ha_status"`
+	StorageLatencyP99 string    `json:"storage_latency_p99"`
+}
+
+// Parse decodes raw JSON produced by the LLM into an AnalysisReport and
+// validates it. Callers should retry the LLM call with the returned
+// error appended to the prompt when this fails.
+func Parse(raw []byte) (AnalysisReport, error) {
+	var r AnalysisReport
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return r, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if err := r.Validate(); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// Validate checks that required fields are present and well-formed.
+func (r AnalysisReport) Validate() error {
+	switch r.OverallHealth {
+	case HealthOK, HealthDegraded, HealthCritical:
+	default:
+		return fmt.Errorf("invalid overall_health %q (want ok, degraded or critical)", r.OverallHealth)
+	}
+	for i, a := range r.Anomalies {
+		if a.Metric == "" {
+			return fmt.Errorf("anomalies[%d]: missing metric", i)
+		}
+		switch a.Severity {
+		case SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+		default:
+			return fmt.Errorf("anomalies[%d]: invalid severity %q", i, a.Severity)
+		}
+	}
+	return nil
+}
+
+// Schema is a human-readable description of AnalysisReport's JSON shape,
+// suitable for embedding in an LLM prompt so it knows exactly what to
+// return.
+const Schema = `{
+  "overall_health": "ok|degraded|critical",
+  "anomalies": [
+    {
+      "metric": "string, the Prometheus metric name",
+      "severity": "low|medium|high|critical",
+      "evidence": "string, the observed value(s) that triggered this",
+      "suggested_action": "string, what an operator should do"
+    }
+  ],
+  "seal_status": "string, e.g. unsealed, sealed",
+  "ha_status": "string, e.g. active, standby",
+  "storage_latency_p99": "string, e.g. 12ms"
+}`
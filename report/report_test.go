@@ -0,0 +1,66 @@
+package report
+
+import "testing"
+
+func TestParseValid(t *testing.T) {
+	raw := `{
+		"overall_health": "degraded",
+		"anomalies": [
+			{"metric": "vault_expire_num_leases", "severity": "high", "evidence": "grew 40%", "suggested_action": "investigate lease churn"}
+		],
+		"seal_status": "unsealed",
+		"ha_status": "active",
+		"storage_latency_p99": "12ms"
+	}`
+	rep, err := Parse([]byte(raw))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if rep.OverallHealth != HealthDegraded {
+		t.Errorf("OverallHealth = %q, want %q", rep.OverallHealth, HealthDegraded)
+	}
+	if len(rep.Anomalies) != 1 || rep.Anomalies[0].Metric != "vault_expire_num_leases" {
+		t.Errorf("unexpected anomalies: %+v", rep.Anomalies)
+	}
+}
+
+func TestParseInvalidJSON(t *testing.T) {
+	if _, err := Parse([]byte(`not json`)); err == nil {
+		t.Fatal("expected an error for malformed JSON, got nil")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		rep     AnalysisReport
+		wantErr bool
+	}{
+		{"valid, no anomalies", AnalysisReport{OverallHealth: HealthOK}, false},
+		{"invalid overall_health", AnalysisReport{OverallHealth: "fine"}, true},
+		{
+			"anomaly missing metric",
+			AnalysisReport{OverallHealth: HealthOK, Anomalies: []Anomaly{{Severity: SeverityLow}}},
+			true,
+		},
+		{
+			"anomaly invalid severity",
+			AnalysisReport{OverallHealth: HealthOK, Anomalies: []Anomaly{{Metric: "x", Severity: "urgent"}}},
+			true,
+		},
+		{
+			"anomaly valid",
+			AnalysisReport{OverallHealth: HealthCritical, Anomalies: []Anomaly{{Metric: "x", Severity: SeverityCritical}}},
+			false,
+		},
+	}
+	for _, c := range cases {
+		err := c.rep.Validate()
+		if c.wantErr && err == nil {
+			t.Errorf("%s: expected an error, got nil", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
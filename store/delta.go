@@ -0,0 +1,56 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MetricDelta is the change in a single watched metric between two
+// snapshots.
+type MetricDelta struct {
+	Prev         float64
+	Curr         float64
+	AbsoluteDiff float64
+	PercentDiff  float64
+}
+
+// Delta is the change in all watched metrics between two snapshots.
+type Delta struct {
+	From, To time.Time
+	Changes  map[string]MetricDelta
+}
+
+// CompareWindows computes the change in each watched metric between prev
+// and curr, for use as trend context in the LLM prompt.
+func CompareWindows(prev, curr Snapshot) Delta {
+	changes := make(map[string]MetricDelta, len(curr.Values))
+	for name, cv := range curr.Values {
+		pv := prev.Values[name]
+		d := MetricDelta{Prev: pv, Curr: cv, AbsoluteDiff: cv - pv}
+		if pv != 0 {
+			d.PercentDiff = (cv - pv) / pv * 100
+		}
+		changes[name] = d
+	}
+	return Delta{From: prev.Timestamp, To: curr.Timestamp, Changes: changes}
+}
+
+// Summarize renders the delta as "metric: prev -> curr (+pct%)" lines,
+// sorted by metric name, suitable for embedding directly in an LLM
+// prompt.
+func (d Delta) Summarize() string {
+	names := make([]string, 0, len(d.Changes))
+	for name := range d.Changes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		c := d.Changes[name]
+		fmt.Fprintf(&buf, "%s: %.2f -> %.2f (%+.1f%%)\n", name, c.Prev, c.Curr, c.PercentDiff)
+	}
+	return buf.String()
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareWindows(t *testing.T) {
+	prev := Snapshot{
+		Timestamp: time.Unix(1000, 0),
+		Values: map[string]float64{
+			"vault_expire_num_leases": 100,
+			"vault_core_unsealed":     0,
+		},
+	}
+	curr := Snapshot{
+		Timestamp: time.Unix(1060, 0),
+		Values: map[string]float64{
+			"vault_expire_num_leases": 140,
+			"vault_core_unsealed":     1,
+		},
+	}
+
+	delta := CompareWindows(prev, curr)
+	if delta.From != prev.Timestamp || delta.To != curr.Timestamp {
+		t.Errorf("delta window = [%v, %v], want [%v, %v]", delta.From, delta.To, prev.Timestamp, curr.Timestamp)
+	}
+
+	leases := delta.Changes["vault_expire_num_leases"]
+	if leases.AbsoluteDiff != 40 {
+		t.Errorf("AbsoluteDiff = %v, want 40", leases.AbsoluteDiff)
+	}
+	if leases.PercentDiff != 40 {
+		t.Errorf("PercentDiff = %v, want 40", leases.PercentDiff)
+	}
+}
+
+func TestCompareWindowsDivideByZero(t *testing.T) {
+	prev := Snapshot{Timestamp: time.Unix(1000, 0), Values: map[string]float64{"vault_core_unsealed": 0}}
+	curr := Snapshot{Timestamp: time.Unix(1060, 0), Values: map[string]float64{"vault_core_unsealed": 1}}
+
+	delta := CompareWindows(prev, curr)
+	unsealed := delta.Changes["vault_core_unsealed"]
+	if unsealed.PercentDiff != 0 {
+		t.Errorf("PercentDiff with a zero previous value = %v, want 0 (no divide-by-zero)", unsealed.PercentDiff)
+	}
+	if unsealed.AbsoluteDiff != 1 {
+		t.Errorf("AbsoluteDiff = %v, want 1", unsealed.AbsoluteDiff)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	prev := Snapshot{Timestamp: time.Unix(1000, 0), Values: map[string]float64{"a": 10, "b": 5}}
+	curr := Snapshot{Timestamp: time.Unix(1060, 0), Values: map[string]float64{"a": 20, "b": 5}}
+
+	summary := CompareWindows(prev, curr).Summarize()
+	wantLines := []string{
+		"a: 10.00 -> 20.00 (+100.0%)\n",
+		"b: 5.00 -> 5.00 (+0.0%)\n",
+	}
+	want := wantLines[0] + wantLines[1]
+	if summary != want {
+		t.Errorf("Summarize() =\n%q\nwant\n%q", summary, want)
+	}
+}
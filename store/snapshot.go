@@ -0,0 +1,65 @@
+// Package store persists parsed scrapes to an embedded BoltDB database
+// and computes deltas between them, so the LLM prompt can include
+// trends ("lease count grew 40% since the last scrape") instead of only
+// a single point-in-time view.
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// WatchedMetrics are the gauges/counters tracked across scrapes for
+// delta analysis. Anything not in this list is still sent to the LLM in
+// the current scrape, it just isn't trended over time.
+var WatchedMetrics = []string{
+	"vault_token_creation",
+	"vault_expire_num_leases",
+	"vault_core_unsealed",
+	"vault_runtime_alloc_bytes",
+	"vault_storage_latency",
+}
+
+// Snapshot is one scrape's values for the watched metrics, at a point in
+// time.
+type Snapshot struct {
+	Timestamp time.Time          `json:"timestamp"`
+	Values    map[string]float64 `json:"values"`
+}
+
+// ParseSnapshot extracts the watched metrics from a raw Prometheus
+// exposition payload into a Snapshot.
+func ParseSnapshot(ts time.Time, raw []byte) (Snapshot, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(raw))
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("parsing metrics for snapshot: %v", err)
+	}
+
+	values := make(map[string]float64, len(WatchedMetrics))
+	for _, name := range WatchedMetrics {
+		fam, ok := families[name]
+		if !ok || len(fam.Metric) == 0 {
+			continue
+		}
+		values[name] = metricValue(fam.Metric[0])
+	}
+	return Snapshot{Timestamp: ts, Values: values}, nil
+}
+
+func metricValue(m *dto.Metric) float64 {
+	switch {
+	case m.Gauge != nil:
+		return m.Gauge.GetValue()
+	case m.Counter != nil:
+		return m.Counter.GetValue()
+	case m.Untyped != nil:
+		return m.Untyped.GetValue()
+	default:
+		return 0
+	}
+}
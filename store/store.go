@@ -0,0 +1,157 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+var scrapesBucket = []byte("scrapes")
+
+// snapshotKeyLayout is used to key snapshots by timestamp. Unlike
+// time.RFC3339Nano, it pads the fractional seconds to a fixed width
+// instead of stripping trailing zeros, so keys sort lexicographically
+// (as BoltDB cursors require) in the same order as the timestamps they
+// represent.
+const snapshotKeyLayout = "20060102T150405.000000000Z"
+
+func snapshotKey(ts time.Time) []byte {
+	return []byte(ts.UTC().Format(snapshotKeyLayout))
+}
+
+// defaultRetention is how many snapshots Save keeps once no
+// VAULT_METRICS_STORE_RETENTION override is set. At one scrape per
+// minute this is a bit under two days of history.
+const defaultRetention = 2500
+
+// Store is an embedded, on-disk history of parsed scrapes.
+type Store struct {
+	db        *bbolt.DB
+	retention int
+}
+
+// Open opens (creating if necessary) a Store backed by a BoltDB file at
+// path. Save retains the most recent VAULT_METRICS_STORE_RETENTION
+// snapshots (defaultRetention if unset or invalid), garbage-collecting
+// older ones so the store doesn't grow unbounded.
+func Open(path string) (*Store, error) {
+	db, err := bbolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening store at %s: %v", path, err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(scrapesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("initializing store bucket: %v", err)
+	}
+
+	retention := defaultRetention
+	if v := os.Getenv("VAULT_METRICS_STORE_RETENTION"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			retention = n
+		}
+	}
+	return &Store{db: db, retention: retention}, nil
+}
+
+// Close releases the underlying BoltDB file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save persists a snapshot, keyed by its timestamp so scrapes are stored
+// and iterated in chronological order, then deletes the oldest
+// snapshots beyond the Store's retention limit.
+func (s *Store) Save(snap Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %v", err)
+	}
+	key := snapshotKey(snap.Timestamp)
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(scrapesBucket)
+		if err := b.Put(key, data); err != nil {
+			return err
+		}
+		return gc(b, s.retention)
+	})
+}
+
+// gc deletes the oldest entries in b beyond the most recent retention
+// keys. It counts keys by walking the cursor rather than using
+// b.Stats(), since Stats() doesn't reflect puts made earlier in the
+// same transaction (as Save's always are).
+func gc(b *bbolt.Bucket, retention int) error {
+	if retention <= 0 {
+		return nil
+	}
+	c := b.Cursor()
+	count := 0
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		count++
+	}
+	excess := count - retention
+	if excess <= 0 {
+		return nil
+	}
+	for k, _ := c.First(); k != nil && excess > 0; k, _ = c.Next() {
+		if err := c.Delete(); err != nil {
+			return err
+		}
+		excess--
+	}
+	return nil
+}
+
+// Baseline returns the most recent snapshot at or before ts, for
+// pinning comparisons against a known-good point in time (e.g. via
+// --baseline) instead of always diffing against the previous scrape.
+func (s *Store) Baseline(ts time.Time) (Snapshot, error) {
+	key := snapshotKey(ts)
+	var snap Snapshot
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(scrapesBucket).Cursor()
+		k, v := c.Seek(key)
+		if k == nil || string(k) > string(key) {
+			k, v = c.Prev()
+		}
+		if k == nil {
+			return fmt.Errorf("no snapshot found at or before %s", ts.Format(time.RFC3339))
+		}
+		return json.Unmarshal(v, &snap)
+	})
+	return snap, err
+}
+
+// Recent returns up to n of the most recently saved snapshots, ordered
+// oldest first.
+func (s *Store) Recent(n int) ([]Snapshot, error) {
+	var newestFirst []Snapshot
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(scrapesBucket).Cursor()
+		for k, v := c.Last(); k != nil && len(newestFirst) < n; k, v = c.Prev() {
+			var snap Snapshot
+			if err := json.Unmarshal(v, &snap); err != nil {
+				return fmt.Errorf("decoding snapshot %s: %v", k, err)
+			}
+			newestFirst = append(newestFirst, snap)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	oldestFirst := make([]Snapshot, len(newestFirst))
+	for i, snap := range newestFirst {
+		oldestFirst[len(newestFirst)-1-i] = snap
+	}
+	return oldestFirst, nil
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	st, err := Open(filepath.Join(t.TempDir(), "store.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+// TestRecentChronologicalOrder guards against snapshot keys sorting by
+// string rather than by time: two snapshots in the same second, saved
+// in chronological order, must come back from Recent in that same
+// order.
+func TestRecentChronologicalOrder(t *testing.T) {
+	st := openTestStore(t)
+
+	base := time.Date(2024, 1, 1, 15, 4, 5, 0, time.UTC)
+	t1 := base.Add(100 * time.Millisecond)
+	t2 := base.Add(120 * time.Millisecond)
+
+	if err := st.Save(Snapshot{Timestamp: t1, Values: map[string]float64{"a": 1}}); err != nil {
+		t.Fatalf("Save(t1): %v", err)
+	}
+	if err := st.Save(Snapshot{Timestamp: t2, Values: map[string]float64{"a": 2}}); err != nil {
+		t.Fatalf("Save(t2): %v", err)
+	}
+
+	recent, err := st.Recent(2)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(recent) != 2 {
+		t.Fatalf("Recent returned %d snapshots, want 2", len(recent))
+	}
+	if !recent[0].Timestamp.Equal(t1) || !recent[1].Timestamp.Equal(t2) {
+		t.Errorf("Recent(2) = [%v, %v], want [%v, %v]", recent[0].Timestamp, recent[1].Timestamp, t1, t2)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	st := openTestStore(t)
+
+	base := time.Date(2024, 1, 1, 15, 4, 5, 0, time.UTC)
+	older := base.Add(100 * time.Millisecond)
+	newer := base.Add(120 * time.Millisecond)
+
+	if err := st.Save(Snapshot{Timestamp: older, Values: map[string]float64{"a": 1}}); err != nil {
+		t.Fatalf("Save(older): %v", err)
+	}
+	if err := st.Save(Snapshot{Timestamp: newer, Values: map[string]float64{"a": 2}}); err != nil {
+		t.Fatalf("Save(newer): %v", err)
+	}
+
+	snap, err := st.Baseline(older.Add(5 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("Baseline: %v", err)
+	}
+	if !snap.Timestamp.Equal(older) {
+		t.Errorf("Baseline() = %v, want the most recent snapshot at or before it (%v)", snap.Timestamp, older)
+	}
+}
+
+// TestGCRetainsNewest ensures gc evicts the oldest snapshots, not
+// whichever happen to sort first by a broken key scheme.
+func TestGCRetainsNewest(t *testing.T) {
+	st := openTestStore(t)
+	st.retention = 2
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		ts := base.Add(time.Duration(i) * time.Second)
+		if err := st.Save(Snapshot{Timestamp: ts, Values: map[string]float64{"a": float64(i)}}); err != nil {
+			t.Fatalf("Save(%d): %v", i, err)
+		}
+	}
+
+	recent, err := st.Recent(10)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(recent) != 2 {
+		t.Fatalf("got %d surviving snapshots, want 2", len(recent))
+	}
+	want := []time.Time{base.Add(3 * time.Second), base.Add(4 * time.Second)}
+	for i, snap := range recent {
+		if !snap.Timestamp.Equal(want[i]) {
+			t.Errorf("surviving snapshot %d = %v, want %v", i, snap.Timestamp, want[i])
+		}
+	}
+}